@@ -16,6 +16,11 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/OpenIMSDK/tools/log"
@@ -23,24 +28,37 @@ import (
 	"github.com/dtm-labs/rockscache"
 	"github.com/redis/go-redis/v9"
 
-	"github.com/OpenIMSDK/tools/utils"
-
 	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 )
 
 const (
-	groupExpireTime     = time.Second * 60 * 60 * 12
-	groupInfoKey        = "GROUP_INFO:"
-	groupMemberIDsKey   = "GROUP_MEMBER_IDS:"
-	groupMembersHashKey = "GROUP_MEMBERS_HASH2:"
-	groupMemberInfoKey  = "GROUP_MEMBER_INFO:"
-	joinedGroupsKey     = "JOIN_GROUPS_KEY:"
-	groupMemberNumKey   = "GROUP_MEMBER_NUM_CACHE:"
+	groupExpireTime         = time.Second * 60 * 60 * 12
+	groupInfoKey            = "GROUP_INFO:"
+	groupMemberIDsKey       = "GROUP_MEMBER_IDS:"
+	groupMembersHashKey     = "GROUP_MEMBERS_HASH2:"
+	groupMemberInfoKey      = "GROUP_MEMBER_INFO:"
+	joinedGroupsKey         = "JOIN_GROUPS_KEY:"
+	groupMemberNumKey       = "GROUP_MEMBER_NUM_CACHE:"
+	groupMembersPageKey     = "GROUP_MEMBERS_PAGE_CACHE:"
+	groupMembersPageExpires = time.Second * 30
+	groupVersionKey         = "GROUP_VER:"
 )
 
+// errGroupVersionStale marks a GetGroupMembersSnapshot read whose member
+// version changed between the first and last field it fetched, telling the
+// caller to retry once rather than hand back a torn view.
+var errGroupVersionStale = errors.New("group member version changed during snapshot read")
+
 type GroupCache interface {
 	metaCache
 	NewCache() GroupCache
+	// Batch returns a builder that accumulates key classes across several
+	// Del* calls and flushes them as a single rockscache batch invalidation
+	// plus one L1/pub-sub eviction on ExecDel, instead of one Redis round
+	// trip per DelXxx call. The single-key DelXxx methods below also build
+	// their key sets through Batch() so the key-construction logic lives in
+	// one place.
+	Batch() *GroupCacheBatch
 	GetGroupsInfo(ctx context.Context, groupIDs []string) (groups []*relationtb.GroupModel, err error)
 	GetGroupInfo(ctx context.Context, groupID string) (group *relationtb.GroupModel, err error)
 	DelGroupsInfo(groupIDs ...string) GroupCache
@@ -66,16 +84,42 @@ type GroupCache interface {
 
 	GetGroupMemberNum(ctx context.Context, groupID string) (memberNum int64, err error)
 	DelGroupsMemberNum(groupID ...string) GroupCache
+
+	// GetGroupMembersSnapshot returns a consistent view of member IDs, hash
+	// and count for groupID, re-reading once if the group's member version
+	// changed mid-read.
+	GetGroupMembersSnapshot(ctx context.Context, groupID string) (*GroupMembersSnapshot, error)
+
+	// DelGroupDissolved invalidates every cache class touched when a group
+	// is dissolved (group info, every member's info, member IDs, member num,
+	// members hash) as a single Batch/ExecDel call, instead of chaining the
+	// individual DelXxx methods and paying one Redis round trip per class
+	// per member.
+	DelGroupDissolved(ctx context.Context, groupID string, memberUserIDs []string) error
+}
+
+// GroupMembersSnapshot is a consistent-as-of-Version view of a group's
+// membership, suitable for delta sync between clients: Version only
+// advances when MemberIDs/Hash/Num could have changed, so a client can
+// cheaply tell whether its last-seen snapshot is still current.
+type GroupMembersSnapshot struct {
+	Version   int64
+	MemberIDs []string
+	Hash      uint64
+	Num       int64
 }
 
 type GroupCacheRedis struct {
 	metaCache
-	groupDB        relationtb.GroupModelInterface
-	groupMemberDB  relationtb.GroupMemberModelInterface
-	groupRequestDB relationtb.GroupRequestModelInterface
-	expireTime     time.Duration
-	rcClient       *rockscache.Client
-	hashCode       func(ctx context.Context, groupID string) (uint64, error)
+	groupDB              relationtb.GroupModelInterface
+	groupMemberDB        relationtb.GroupMemberModelInterface
+	groupRequestDB       relationtb.GroupRequestModelInterface
+	expireTime           time.Duration
+	rcClient             *rockscache.Client
+	hashCode             func(ctx context.Context, groupID string) (uint64, error)
+	rdb                  redis.UniversalClient
+	local                *localCache
+	pendingVersionGroups map[string]struct{}
 }
 
 func NewGroupCacheRedis(
@@ -87,24 +131,302 @@ func NewGroupCacheRedis(
 	opts rockscache.Options,
 ) GroupCache {
 	rcClient := rockscache.NewClient(rdb, opts)
+	local := newLocalCache()
+	local.subscribe(rdb)
 
 	return &GroupCacheRedis{
 		rcClient: rcClient, expireTime: groupExpireTime,
 		groupDB: groupDB, groupMemberDB: groupMemberDB, groupRequestDB: groupRequestDB,
-		hashCode:  hashCode,
-		metaCache: NewMetaCacheRedis(rcClient),
+		hashCode:             hashCode,
+		metaCache:            NewMetaCacheRedis(rcClient),
+		rdb:                  rdb,
+		local:                local,
+		pendingVersionGroups: make(map[string]struct{}),
 	}
 }
 
 func (g *GroupCacheRedis) NewCache() GroupCache {
+	pendingVersionGroups := make(map[string]struct{}, len(g.pendingVersionGroups))
+	for groupID := range g.pendingVersionGroups {
+		pendingVersionGroups[groupID] = struct{}{}
+	}
+
 	return &GroupCacheRedis{
-		rcClient:       g.rcClient,
-		expireTime:     g.expireTime,
-		groupDB:        g.groupDB,
-		groupMemberDB:  g.groupMemberDB,
-		groupRequestDB: g.groupRequestDB,
-		metaCache:      NewMetaCacheRedis(g.rcClient, g.metaCache.GetPreDelKeys()...),
+		rcClient:             g.rcClient,
+		expireTime:           g.expireTime,
+		groupDB:              g.groupDB,
+		groupMemberDB:        g.groupMemberDB,
+		groupRequestDB:       g.groupRequestDB,
+		hashCode:             g.hashCode,
+		rdb:                  g.rdb,
+		local:                g.local,
+		pendingVersionGroups: pendingVersionGroups,
+		metaCache:            NewMetaCacheRedis(g.rcClient, g.metaCache.GetPreDelKeys()...),
+	}
+}
+
+// delLocalAndPublish evicts keys from this instance's L1 cache immediately
+// and asynchronously tells every other instance to do the same.
+func (g *GroupCacheRedis) delLocalAndPublish(keys ...string) {
+	g.local.del(keys...)
+	publishLocalInvalidate(g.rdb, keys)
+}
+
+// ExecDel shadows metaCache's promoted ExecDel so the L1 evict/publish and
+// member-version bump happen at the point the real deferred double-delete
+// against rockscache executes, not when a DelXxx call merely records the
+// pending keys via AddKeys. Evicting L1 that early would let a concurrent
+// reader between AddKeys and the real delete repopulate it with the
+// pre-write value and fan that out to every instance for localCacheTTL.
+func (g *GroupCacheRedis) ExecDel(ctx context.Context) error {
+	keys := g.metaCache.GetPreDelKeys()
+	if err := g.metaCache.ExecDel(ctx); err != nil {
+		return err
+	}
+	g.delLocalAndPublish(keys...)
+	if len(g.pendingVersionGroups) > 0 {
+		groupIDs := make([]string, 0, len(g.pendingVersionGroups))
+		for groupID := range g.pendingVersionGroups {
+			groupIDs = append(groupIDs, groupID)
+		}
+		if err := g.bumpGroupVersions(ctx, groupIDs...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *GroupCacheRedis) getGroupVersionKey(groupID string) string {
+	return groupVersionKey + groupID
+}
+
+// getGroupVersion reads a group's member version, treating an unset key (no
+// Del* has ever bumped it) as version 0.
+func (g *GroupCacheRedis) getGroupVersion(ctx context.Context, groupID string) (int64, error) {
+	version, err := g.rdb.Get(ctx, g.getGroupVersionKey(groupID)).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// bumpGroupVersions advances the member version of each groupID so that any
+// concurrent GetGroupMembersSnapshot read notices it raced a membership
+// change. It must complete before ExecDel returns: GetGroupMembersSnapshot's
+// before/after version check only catches a concurrent Del if the bump is
+// visible by the time ExecDel's caller proceeds, so it pipelines the Incrs
+// and waits on ctx rather than firing them from a detached goroutine. Each
+// Incr is paired with an Expire so a version key for a group that's since
+// been dissolved doesn't linger in Redis forever.
+func (g *GroupCacheRedis) bumpGroupVersions(ctx context.Context, groupIDs ...string) error {
+	if g.rdb == nil || len(groupIDs) == 0 {
+		return nil
+	}
+	pipe := g.rdb.Pipeline()
+	for _, groupID := range groupIDs {
+		key := g.getGroupVersionKey(groupID)
+		pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, groupExpireTime)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.ZWarn(ctx, "bump group member version failed", err, "groupIDs", groupIDs)
+
+		return err
+	}
+
+	return nil
+}
+
+// GetGroupMembersSnapshot returns member IDs, hash and count for groupID as
+// of a single version: it reads the version before and after the three
+// underlying cache lookups and retries once if they disagree, so callers
+// doing delta sync never observe a torn view (e.g. hash already bumped but
+// member IDs still the old set).
+func (g *GroupCacheRedis) GetGroupMembersSnapshot(ctx context.Context, groupID string) (*GroupMembersSnapshot, error) {
+	read := func() (*GroupMembersSnapshot, error) {
+		before, err := g.getGroupVersion(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		memberIDs, err := g.GetGroupMemberIDs(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		hash, err := g.GetGroupMembersHash(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		num, err := g.GetGroupMemberNum(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		after, err := g.getGroupVersion(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if before != after {
+			return nil, errGroupVersionStale
+		}
+
+		return &GroupMembersSnapshot{Version: after, MemberIDs: memberIDs, Hash: hash, Num: num}, nil
+	}
+
+	snapshot, err := read()
+	if errors.Is(err, errGroupVersionStale) {
+		snapshot, err = read()
 	}
+
+	return snapshot, err
+}
+
+func (g *GroupCacheRedis) Batch() *GroupCacheBatch {
+	return &GroupCacheBatch{cache: g, keys: make(map[string]struct{}), versionGroups: make(map[string]struct{})}
+}
+
+// GroupCacheBatch accumulates the key classes touched by several fluent
+// DelXxx calls (e.g. dissolving a group: info, every member's info, member
+// IDs, member num, members hash all change at once) and flushes them as one
+// rockscache batch invalidation on ExecDel, instead of each DelXxx hitting
+// Redis on its own.
+type GroupCacheBatch struct {
+	cache         *GroupCacheRedis
+	keys          map[string]struct{}
+	versionGroups map[string]struct{}
+}
+
+func (b *GroupCacheBatch) addKeys(keys ...string) *GroupCacheBatch {
+	for _, key := range keys {
+		b.keys[key] = struct{}{}
+	}
+
+	return b
+}
+
+// addVersionGroups marks groupIDs whose member set/hash/num changed so
+// ExecDel bumps their version once alongside the key invalidation.
+func (b *GroupCacheBatch) addVersionGroups(groupIDs ...string) *GroupCacheBatch {
+	for _, groupID := range groupIDs {
+		b.versionGroups[groupID] = struct{}{}
+	}
+
+	return b
+}
+
+// keySlice and versionGroupSlice flatten the accumulated sets. The single
+// fluent DelXxx methods below use these, via Batch(), as the single place key
+// classes are built instead of duplicating the getGroupXxxKey calls.
+func (b *GroupCacheBatch) keySlice() []string {
+	keys := make([]string, 0, len(b.keys))
+	for key := range b.keys {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (b *GroupCacheBatch) versionGroupSlice() []string {
+	groupIDs := make([]string, 0, len(b.versionGroups))
+	for groupID := range b.versionGroups {
+		groupIDs = append(groupIDs, groupID)
+	}
+
+	return groupIDs
+}
+
+func (b *GroupCacheBatch) DelGroupsInfo(groupIDs ...string) *GroupCacheBatch {
+	for _, groupID := range groupIDs {
+		b.addKeys(b.cache.getGroupInfoKey(groupID))
+	}
+
+	return b
+}
+
+func (b *GroupCacheBatch) DelGroupMembersHash(groupIDs ...string) *GroupCacheBatch {
+	for _, groupID := range groupIDs {
+		b.addKeys(b.cache.getGroupMembersHashKey(groupID))
+	}
+	b.addVersionGroups(groupIDs...)
+
+	return b
+}
+
+func (b *GroupCacheBatch) DelGroupMemberIDs(groupIDs ...string) *GroupCacheBatch {
+	for _, groupID := range groupIDs {
+		b.addKeys(b.cache.getGroupMemberIDsKey(groupID))
+	}
+	b.addVersionGroups(groupIDs...)
+
+	return b
+}
+
+func (b *GroupCacheBatch) DelJoinedGroupID(userIDs ...string) *GroupCacheBatch {
+	for _, userID := range userIDs {
+		b.addKeys(b.cache.getJoinedGroupsKey(userID))
+	}
+
+	return b
+}
+
+func (b *GroupCacheBatch) DelGroupMembersInfo(groupID string, userIDs ...string) *GroupCacheBatch {
+	for _, userID := range userIDs {
+		b.addKeys(b.cache.getGroupMemberInfoKey(groupID, userID))
+	}
+
+	return b
+}
+
+func (b *GroupCacheBatch) DelGroupsMemberNum(groupIDs ...string) *GroupCacheBatch {
+	for _, groupID := range groupIDs {
+		b.addKeys(b.cache.getGroupMemberNumKey(groupID))
+	}
+	b.addVersionGroups(groupIDs...)
+
+	return b
+}
+
+// ExecDel tags every accumulated key as deleted in one rockscache batch call
+// (TagAsDeletedBatch2 pipelines/Lua-UNLINKs internally rather than issuing
+// one DEL per key), bumps the member version of every affected group once,
+// and evicts/broadcasts the same key set to the L1 cache once.
+func (b *GroupCacheBatch) ExecDel(ctx context.Context) error {
+	if len(b.keys) == 0 {
+		return nil
+	}
+	keys := b.keySlice()
+	if err := b.cache.rcClient.TagAsDeletedBatch2(ctx, keys); err != nil {
+		return err
+	}
+	b.cache.delLocalAndPublish(keys...)
+	if len(b.versionGroups) > 0 {
+		if err := b.cache.bumpGroupVersions(ctx, b.versionGroupSlice()...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DelGroupDissolved is the batched counterpart to chaining DelGroupsInfo,
+// DelGroupMembersHash, DelGroupMemberIDs, DelGroupsMemberNum and
+// DelGroupMembersInfo individually: dissolving a group with thousands of
+// members used to mean one Redis round trip per key class per member, this
+// issues a single rockscache batch invalidation instead. The group
+// dissolution RPC flow is the intended caller: it should replace its
+// chained DelXxx(...).ExecDel(ctx) calls with this single method.
+func (g *GroupCacheRedis) DelGroupDissolved(ctx context.Context, groupID string, memberUserIDs []string) error {
+	return g.Batch().
+		DelGroupsInfo(groupID).
+		DelGroupMembersHash(groupID).
+		DelGroupMemberIDs(groupID).
+		DelGroupsMemberNum(groupID).
+		DelGroupMembersInfo(groupID, memberUserIDs...).
+		ExecDel(ctx)
 }
 
 func (g *GroupCacheRedis) getGroupInfoKey(groupID string) string {
@@ -155,35 +477,74 @@ func (g *GroupCacheRedis) GetGroupMemberIndex(groupMember *relationtb.GroupMembe
 
 // / groupInfo.
 func (g *GroupCacheRedis) GetGroupsInfo(ctx context.Context, groupIDs []string) (groups []*relationtb.GroupModel, err error) {
-	return batchGetCache2(ctx, g.rcClient, g.expireTime, groupIDs, func(groupID string) string {
+	hit := make(map[string]*relationtb.GroupModel, len(groupIDs))
+	missIDs := make([]string, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		if v, ok := g.local.get(g.getGroupInfoKey(groupID)); ok {
+			hit[groupID] = v.(*relationtb.GroupModel)
+		} else {
+			missIDs = append(missIDs, groupID)
+		}
+	}
+	fetched, err := batchGetCache2(ctx, g.rcClient, g.expireTime, missIDs, func(groupID string) string {
 		return g.getGroupInfoKey(groupID)
 	}, func(ctx context.Context, groupID string) (*relationtb.GroupModel, error) {
 		return g.groupDB.Take(ctx, groupID)
 	})
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range fetched {
+		hit[group.GroupID] = group
+		g.local.set(g.getGroupInfoKey(group.GroupID), group)
+	}
+	groups = make([]*relationtb.GroupModel, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		if group, ok := hit[groupID]; ok {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups, nil
 }
 
 func (g *GroupCacheRedis) GetGroupInfo(ctx context.Context, groupID string) (group *relationtb.GroupModel, err error) {
-	return getCache(ctx, g.rcClient, g.getGroupInfoKey(groupID), g.expireTime, func(ctx context.Context) (*relationtb.GroupModel, error) {
+	key := g.getGroupInfoKey(groupID)
+	if v, ok := g.local.get(key); ok {
+		return v.(*relationtb.GroupModel), nil
+	}
+	group, err = getCache(ctx, g.rcClient, key, g.expireTime, func(ctx context.Context) (*relationtb.GroupModel, error) {
 		return g.groupDB.Take(ctx, groupID)
 	})
+	if err == nil {
+		g.local.set(key, group)
+	}
+
+	return group, err
 }
 
 func (g *GroupCacheRedis) DelGroupsInfo(groupIDs ...string) GroupCache {
 	newGroupCache := g.NewCache()
-	keys := make([]string, 0, len(groupIDs))
-	for _, groupID := range groupIDs {
-		keys = append(keys, g.getGroupInfoKey(groupID))
-	}
-	newGroupCache.AddKeys(keys...)
+	newGroupCache.AddKeys(g.Batch().DelGroupsInfo(groupIDs...).keySlice()...)
 
 	return newGroupCache
 }
 
-// groupMembersHash.
+// groupMembersHash. L1-cached like GetGroupMemberInfo/GetGroupMemberNum:
+// GetGroupMembersSnapshot calls this on every read, making it just as hot.
 func (g *GroupCacheRedis) GetGroupMembersHash(ctx context.Context, groupID string) (hashCode uint64, err error) {
-	return getCache(ctx, g.rcClient, g.getGroupMembersHashKey(groupID), g.expireTime, func(ctx context.Context) (uint64, error) {
+	key := g.getGroupMembersHashKey(groupID)
+	if v, ok := g.local.get(key); ok {
+		return v.(uint64), nil
+	}
+	hashCode, err = getCache(ctx, g.rcClient, key, g.expireTime, func(ctx context.Context) (uint64, error) {
 		return g.hashCode(ctx, groupID)
 	})
+	if err == nil {
+		g.local.set(key, hashCode)
+	}
+
+	return hashCode, err
 }
 
 func (g *GroupCacheRedis) GetGroupMemberHashMap(ctx context.Context, groupIDs []string) (map[string]*relationtb.GroupSimpleUserID, error) {
@@ -205,17 +566,31 @@ func (g *GroupCacheRedis) GetGroupMemberHashMap(ctx context.Context, groupIDs []
 }
 
 func (g *GroupCacheRedis) DelGroupMembersHash(groupID string) GroupCache {
+	batch := g.Batch().DelGroupMembersHash(groupID)
 	cache := g.NewCache()
-	cache.AddKeys(g.getGroupMembersHashKey(groupID))
+	cache.AddKeys(batch.keySlice()...)
+	for _, id := range batch.versionGroupSlice() {
+		cache.(*GroupCacheRedis).pendingVersionGroups[id] = struct{}{}
+	}
 
 	return cache
 }
 
-// groupMemberIDs.
+// groupMemberIDs. L1-cached like GetGroupMemberInfo/GetGroupMemberNum:
+// GetGroupMembersSnapshot calls this on every read, making it just as hot.
 func (g *GroupCacheRedis) GetGroupMemberIDs(ctx context.Context, groupID string) (groupMemberIDs []string, err error) {
-	return getCache(ctx, g.rcClient, g.getGroupMemberIDsKey(groupID), g.expireTime, func(ctx context.Context) ([]string, error) {
+	key := g.getGroupMemberIDsKey(groupID)
+	if v, ok := g.local.get(key); ok {
+		return v.([]string), nil
+	}
+	groupMemberIDs, err = getCache(ctx, g.rcClient, key, g.expireTime, func(ctx context.Context) ([]string, error) {
 		return g.groupMemberDB.FindMemberUserID(ctx, groupID)
 	})
+	if err == nil {
+		g.local.set(key, groupMemberIDs)
+	}
+
+	return groupMemberIDs, err
 }
 
 func (g *GroupCacheRedis) GetGroupsMemberIDs(ctx context.Context, groupIDs []string) (map[string][]string, error) {
@@ -232,8 +607,12 @@ func (g *GroupCacheRedis) GetGroupsMemberIDs(ctx context.Context, groupIDs []str
 }
 
 func (g *GroupCacheRedis) DelGroupMemberIDs(groupID string) GroupCache {
+	batch := g.Batch().DelGroupMemberIDs(groupID)
 	cache := g.NewCache()
-	cache.AddKeys(g.getGroupMemberIDsKey(groupID))
+	cache.AddKeys(batch.keySlice()...)
+	for _, id := range batch.versionGroupSlice() {
+		cache.(*GroupCacheRedis).pendingVersionGroups[id] = struct{}{}
+	}
 
 	return cache
 }
@@ -245,48 +624,109 @@ func (g *GroupCacheRedis) GetJoinedGroupIDs(ctx context.Context, userID string)
 }
 
 func (g *GroupCacheRedis) DelJoinedGroupID(userIDs ...string) GroupCache {
-	keys := make([]string, 0, len(userIDs))
-	for _, userID := range userIDs {
-		keys = append(keys, g.getJoinedGroupsKey(userID))
-	}
 	cache := g.NewCache()
-	cache.AddKeys(keys...)
+	cache.AddKeys(g.Batch().DelJoinedGroupID(userIDs...).keySlice()...)
 
 	return cache
 }
 
 func (g *GroupCacheRedis) GetGroupMemberInfo(ctx context.Context, groupID, userID string) (groupMember *relationtb.GroupMemberModel, err error) {
-	return getCache(ctx, g.rcClient, g.getGroupMemberInfoKey(groupID, userID), g.expireTime, func(ctx context.Context) (*relationtb.GroupMemberModel, error) {
+	key := g.getGroupMemberInfoKey(groupID, userID)
+	if v, ok := g.local.get(key); ok {
+		return v.(*relationtb.GroupMemberModel), nil
+	}
+	groupMember, err = getCache(ctx, g.rcClient, key, g.expireTime, func(ctx context.Context) (*relationtb.GroupMemberModel, error) {
 		return g.groupMemberDB.Take(ctx, groupID, userID)
 	})
+	if err == nil {
+		g.local.set(key, groupMember)
+	}
+
+	return groupMember, err
 }
 
 func (g *GroupCacheRedis) GetGroupMembersInfo(ctx context.Context, groupID string, userIDs []string) ([]*relationtb.GroupMemberModel, error) {
-	return batchGetCache2(ctx, g.rcClient, g.expireTime, userIDs, func(userID string) string {
+	hit := make(map[string]*relationtb.GroupMemberModel, len(userIDs))
+	missIDs := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if v, ok := g.local.get(g.getGroupMemberInfoKey(groupID, userID)); ok {
+			hit[userID] = v.(*relationtb.GroupMemberModel)
+		} else {
+			missIDs = append(missIDs, userID)
+		}
+	}
+	fetched, err := batchGetCache2(ctx, g.rcClient, g.expireTime, missIDs, func(userID string) string {
 		return g.getGroupMemberInfoKey(groupID, userID)
 	}, func(ctx context.Context, userID string) (*relationtb.GroupMemberModel, error) {
 		return g.groupMemberDB.Take(ctx, groupID, userID)
 	})
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range fetched {
+		hit[member.UserID] = member
+		g.local.set(g.getGroupMemberInfoKey(groupID, member.UserID), member)
+	}
+	groupMembers := make([]*relationtb.GroupMemberModel, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if member, ok := hit[userID]; ok {
+			groupMembers = append(groupMembers, member)
+		}
+	}
+
+	return groupMembers, nil
+}
+
+func (g *GroupCacheRedis) getGroupMembersPageKey(groupID string, pageNumber, showNumber int32, filterHash string) string {
+	return groupMembersPageKey + groupID + ":" + strconv.Itoa(int(pageNumber)) + ":" + strconv.Itoa(int(showNumber)) + ":" + filterHash
 }
 
+// groupMembersPageFilterHash identifies the userIDs filter a page was
+// fetched with, independent of slice order, so the same filter reuses the
+// same cache key. Only nil maps to "all": an empty but non-nil filter yields
+// zero members in PageMembers and so gets a key of its own.
+func groupMembersPageFilterHash(userIDs []string) string {
+	if userIDs == nil {
+		return "all"
+	}
+	sorted := append([]string(nil), userIDs...)
+	sort.Strings(sorted)
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.Join(sorted, ",")))
+
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+type groupMembersPage struct {
+	Total   uint32
+	Members []*relationtb.GroupMemberModel
+}
+
+// GetGroupMembersPage pages group_member on the database side via
+// GroupMemberModelInterface.PageMembers instead of loading every member ID
+// into memory and slicing in Go, so it stays O(page size) regardless of
+// group size. Each (groupID, page, size, filter) combination is cached under
+// a short TTL since pages churn faster than the underlying member set.
 func (g *GroupCacheRedis) GetGroupMembersPage(
 	ctx context.Context,
 	groupID string,
 	userIDs []string,
 	showNumber, pageNumber int32,
 ) (total uint32, groupMembers []*relationtb.GroupMemberModel, err error) {
-	groupMemberIDs, err := g.GetGroupMemberIDs(ctx, groupID)
+	key := g.getGroupMembersPageKey(groupID, pageNumber, showNumber, groupMembersPageFilterHash(userIDs))
+	page, err := getCache(ctx, g.rcClient, key, groupMembersPageExpires, func(ctx context.Context) (groupMembersPage, error) {
+		count, members, err := g.groupMemberDB.PageMembers(ctx, groupID, userIDs, nil, pageNumber, showNumber)
+		if err != nil {
+			return groupMembersPage{}, err
+		}
+
+		return groupMembersPage{Total: uint32(count), Members: members}, nil
+	})
 	if err != nil {
 		return 0, nil, err
 	}
-	if userIDs != nil {
-		userIDs = utils.BothExist(userIDs, groupMemberIDs)
-	} else {
-		userIDs = groupMemberIDs
-	}
-	groupMembers, err = g.GetGroupMembersInfo(ctx, groupID, utils.Paginate(userIDs, int(showNumber), int(showNumber)))
 
-	return uint32(len(userIDs)), groupMembers, err
+	return page.Total, page.Members, nil
 }
 
 func (g *GroupCacheRedis) GetAllGroupMembersInfo(ctx context.Context, groupID string) (groupMembers []*relationtb.GroupMemberModel, err error) {
@@ -307,29 +747,35 @@ func (g *GroupCacheRedis) GetAllGroupMemberInfo(ctx context.Context, groupID str
 }
 
 func (g *GroupCacheRedis) DelGroupMembersInfo(groupID string, userIDs ...string) GroupCache {
-	keys := make([]string, 0, len(userIDs))
-	for _, userID := range userIDs {
-		keys = append(keys, g.getGroupMemberInfoKey(groupID, userID))
-	}
 	cache := g.NewCache()
-	cache.AddKeys(keys...)
+	cache.AddKeys(g.Batch().DelGroupMembersInfo(groupID, userIDs...).keySlice()...)
 
 	return cache
 }
 
 func (g *GroupCacheRedis) GetGroupMemberNum(ctx context.Context, groupID string) (memberNum int64, err error) {
-	return getCache(ctx, g.rcClient, g.getGroupMemberNumKey(groupID), g.expireTime, func(ctx context.Context) (int64, error) {
+	key := g.getGroupMemberNumKey(groupID)
+	if v, ok := g.local.get(key); ok {
+		return v.(int64), nil
+	}
+	memberNum, err = getCache(ctx, g.rcClient, key, g.expireTime, func(ctx context.Context) (int64, error) {
 		return g.groupMemberDB.TakeGroupMemberNum(ctx, groupID)
 	})
+	if err == nil {
+		g.local.set(key, memberNum)
+	}
+
+	return memberNum, err
 }
 
 func (g *GroupCacheRedis) DelGroupsMemberNum(groupID ...string) GroupCache {
-	keys := make([]string, 0, len(groupID))
-	for _, groupID := range groupID {
-		keys = append(keys, g.getGroupMemberNumKey(groupID))
-	}
+	batch := g.Batch().DelGroupsMemberNum(groupID...)
 	cache := g.NewCache()
-	cache.AddKeys(keys...)
+	cache.AddKeys(batch.keySlice()...)
+	redisCache := cache.(*GroupCacheRedis)
+	for _, id := range batch.versionGroupSlice() {
+		redisCache.pendingVersionGroups[id] = struct{}{}
+	}
 
 	return cache
 }
@@ -0,0 +1,192 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestGroupCacheBatch() *GroupCacheBatch {
+	return (&GroupCacheRedis{}).Batch()
+}
+
+// fakeVersionRDB returns a scripted sequence of GROUP_VER replies, one per
+// Get call, letting tests drive GetGroupMembersSnapshot's before/after
+// version comparison without a real Redis.
+type fakeVersionRDB struct {
+	redis.UniversalClient
+	versions []int64
+	calls    int
+}
+
+func (f *fakeVersionRDB) Get(ctx context.Context, key string) *redis.StringCmd {
+	cmd := redis.NewStringCmd(ctx)
+	cmd.SetVal(strconv.FormatInt(f.versions[f.calls], 10))
+	f.calls++
+
+	return cmd
+}
+
+// TestGetGroupMembersSnapshotRetriesOnTornRead checks that
+// GetGroupMembersSnapshot retries exactly once when the GROUP_VER read
+// before its member lookups disagrees with the read after, and returns
+// the version it settled on.
+func TestGetGroupMembersSnapshotRetriesOnTornRead(t *testing.T) {
+	rdb := &fakeVersionRDB{versions: []int64{1, 2, 2, 2}}
+	g := &GroupCacheRedis{local: newLocalCache(), rdb: rdb}
+	g.local.set(g.getGroupMemberIDsKey("g1"), []string{"u1"})
+	g.local.set(g.getGroupMembersHashKey("g1"), uint64(7))
+	g.local.set(g.getGroupMemberNumKey("g1"), int64(1))
+
+	snapshot, err := g.GetGroupMembersSnapshot(context.Background(), "g1")
+	if err != nil {
+		t.Fatalf("GetGroupMembersSnapshot() error = %v", err)
+	}
+	if snapshot.Version != 2 {
+		t.Fatalf("Version = %d, want 2", snapshot.Version)
+	}
+	if rdb.calls != 4 {
+		t.Fatalf("GROUP_VER Get calls = %d, want 4 (one torn read, one clean retry)", rdb.calls)
+	}
+}
+
+// TestGetGroupMembersSnapshotNoRetryOnConsistentRead checks the common case:
+// a stable version doesn't trigger a second read.
+func TestGetGroupMembersSnapshotNoRetryOnConsistentRead(t *testing.T) {
+	rdb := &fakeVersionRDB{versions: []int64{5, 5}}
+	g := &GroupCacheRedis{local: newLocalCache(), rdb: rdb}
+	g.local.set(g.getGroupMemberIDsKey("g1"), []string{"u1"})
+	g.local.set(g.getGroupMembersHashKey("g1"), uint64(7))
+	g.local.set(g.getGroupMemberNumKey("g1"), int64(1))
+
+	snapshot, err := g.GetGroupMembersSnapshot(context.Background(), "g1")
+	if err != nil {
+		t.Fatalf("GetGroupMembersSnapshot() error = %v", err)
+	}
+	if snapshot.Version != 5 {
+		t.Fatalf("Version = %d, want 5", snapshot.Version)
+	}
+	if rdb.calls != 2 {
+		t.Fatalf("GROUP_VER Get calls = %d, want 2 (no retry)", rdb.calls)
+	}
+}
+
+// TestGetGroupMemberIDsAndHashL1Hit checks that GetGroupMemberIDs and
+// GetGroupMembersHash return the L1-cached value without touching rcClient,
+// the same short-circuit GetGroupMemberInfo/GetGroupMemberNum already use.
+// GetGroupMembersSnapshot calls both on every read, so an L1 miss there
+// would hit Redis on every snapshot even for a hot group.
+func TestGetGroupMemberIDsAndHashL1Hit(t *testing.T) {
+	g := &GroupCacheRedis{local: newLocalCache()}
+
+	g.local.set(g.getGroupMemberIDsKey("g1"), []string{"u1", "u2"})
+	ids, err := g.GetGroupMemberIDs(nil, "g1") //nolint:staticcheck // nil ctx never reaches rcClient on an L1 hit
+	if err != nil {
+		t.Fatalf("GetGroupMemberIDs() error = %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "u1" || ids[1] != "u2" {
+		t.Fatalf("GetGroupMemberIDs() = %v, want [u1 u2]", ids)
+	}
+
+	g.local.set(g.getGroupMembersHashKey("g1"), uint64(42))
+	hash, err := g.GetGroupMembersHash(nil, "g1") //nolint:staticcheck // nil ctx never reaches rcClient on an L1 hit
+	if err != nil {
+		t.Fatalf("GetGroupMembersHash() error = %v", err)
+	}
+	if hash != 42 {
+		t.Fatalf("GetGroupMembersHash() = %v, want 42", hash)
+	}
+}
+
+// TestGroupCacheBatchDedup checks that chaining several DelXxx calls across
+// overlapping groups collapses to one key/version entry per class, which is
+// what lets ExecDel issue a single rockscache batch call instead of one per
+// DelXxx invocation.
+func TestGroupCacheBatchDedup(t *testing.T) {
+	b := newTestGroupCacheBatch().
+		DelGroupsInfo("g1", "g2").
+		DelGroupMembersHash("g1", "g2").
+		DelGroupMemberIDs("g1").
+		DelGroupsMemberNum("g1", "g2").
+		DelGroupMembersInfo("g1", "u1", "u2").
+		DelGroupMembersInfo("g1", "u1") // duplicate userID, must not double-count
+
+	keys := b.keySlice()
+	sort.Strings(keys)
+	wantKeys := []string{
+		"GROUP_INFO:g1", "GROUP_INFO:g2",
+		"GROUP_MEMBERS_HASH2:g1", "GROUP_MEMBERS_HASH2:g2",
+		"GROUP_MEMBER_IDS:g1",
+		"GROUP_MEMBER_INFO:g1-u1", "GROUP_MEMBER_INFO:g1-u2",
+		"GROUP_MEMBER_NUM_CACHE:g1", "GROUP_MEMBER_NUM_CACHE:g2",
+	}
+	sort.Strings(wantKeys)
+	if len(keys) != len(wantKeys) {
+		t.Fatalf("keySlice() = %v, want %v", keys, wantKeys)
+	}
+	for i := range keys {
+		if keys[i] != wantKeys[i] {
+			t.Fatalf("keySlice() = %v, want %v", keys, wantKeys)
+		}
+	}
+
+	versionGroups := b.versionGroupSlice()
+	sort.Strings(versionGroups)
+	wantVersionGroups := []string{"g1", "g2"}
+	if len(versionGroups) != len(wantVersionGroups) {
+		t.Fatalf("versionGroupSlice() = %v, want %v", versionGroups, wantVersionGroups)
+	}
+	for i := range versionGroups {
+		if versionGroups[i] != wantVersionGroups[i] {
+			t.Fatalf("versionGroupSlice() = %v, want %v", versionGroups, wantVersionGroups)
+		}
+	}
+}
+
+// TestDelGroupDissolvedKeys builds the same Batch chain DelGroupDissolved
+// uses (without calling it, since its ExecDel needs rockscache) and checks
+// that the chain covers every key class a dissolved group needs invalidated.
+func TestDelGroupDissolvedKeys(t *testing.T) {
+	cache := &GroupCacheRedis{}
+	b := cache.Batch().
+		DelGroupsInfo("g1").
+		DelGroupMembersHash("g1").
+		DelGroupMemberIDs("g1").
+		DelGroupsMemberNum("g1").
+		DelGroupMembersInfo("g1", "u1", "u2")
+
+	keys := b.keySlice()
+	want := map[string]bool{
+		"GROUP_INFO:g1":             true,
+		"GROUP_MEMBERS_HASH2:g1":    true,
+		"GROUP_MEMBER_IDS:g1":       true,
+		"GROUP_MEMBER_NUM_CACHE:g1": true,
+		"GROUP_MEMBER_INFO:g1-u1":   true,
+		"GROUP_MEMBER_INFO:g1-u2":   true,
+	}
+	if len(keys) != len(want) {
+		t.Fatalf("keySlice() = %v, want keys for %v", keys, want)
+	}
+	for _, key := range keys {
+		if !want[key] {
+			t.Fatalf("unexpected key %q in DelGroupDissolved batch", key)
+		}
+	}
+}
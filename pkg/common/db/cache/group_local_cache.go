@@ -0,0 +1,218 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// localCacheShardNum keeps per-shard lock contention low without paying
+	// for a lock-free map; group reads are bursty across many groupIDs so a
+	// single mutex would serialize hot-path hits.
+	localCacheShardNum = 32
+	// localCacheTTL is intentionally much shorter than groupExpireTime: the L1
+	// cache only needs to survive a single message fan-out burst, staleness
+	// beyond that is not worth the extra memory.
+	localCacheTTL = time.Second * 10
+	// groupLocalInvalidateChannel carries comma-joined rockscache keys so
+	// every API/RPC instance can evict the same entries from its L1 cache
+	// after one instance calls a Del* method.
+	groupLocalInvalidateChannel = "GROUP_CACHE_L1_INVALIDATE"
+	// localCacheSubscribePingInterval bounds how long a dead subscription can
+	// go unnoticed: Channel() doesn't close on its own when go-redis silently
+	// reconnects it, so a periodic Ping is the only reliable disconnect signal.
+	localCacheSubscribePingInterval = time.Second * 15
+	// localCacheReapInterval bounds how long an expired entry can sit in a
+	// shard map after nothing reads it again: get() only skips expired
+	// entries lazily, it never deletes them, so without this sweep a
+	// long-running process touching many groups/members over time would grow
+	// its L1 cache without bound.
+	localCacheReapInterval = time.Second * 30
+)
+
+type localCacheEntry struct {
+	value   any
+	expires time.Time
+	gen     uint64
+}
+
+type localCacheShard struct {
+	mu    sync.RWMutex
+	items map[string]localCacheEntry
+}
+
+// localCache is a small sharded, TTL'd in-process cache that sits in front of
+// rockscache/Redis for hot GroupCacheRedis reads (group info, member counts,
+// member info looked up repeatedly during message fan-out). It is purely an
+// optimization: a miss, expiry, or generation mismatch always falls back to
+// the normal rockscache path, so it never needs to be the source of truth.
+// A background reaper purges expired entries so a long-running process
+// touching many groups over time stays bounded instead of growing forever.
+type localCache struct {
+	shards [localCacheShardNum]*localCacheShard
+	gen    uint64
+}
+
+func newLocalCache() *localCache {
+	lc := &localCache{}
+	for i := range lc.shards {
+		lc.shards[i] = &localCacheShard{items: make(map[string]localCacheEntry)}
+	}
+	go lc.reapExpiredLoop()
+
+	return lc
+}
+
+// reapExpiredLoop periodically purges expired entries so the shard maps stay
+// bounded to roughly what's been touched in the last localCacheTTL +
+// localCacheReapInterval, instead of growing for the life of the process.
+func (l *localCache) reapExpiredLoop() {
+	ticker := time.NewTicker(localCacheReapInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		l.reapExpired(now)
+	}
+}
+
+func (l *localCache) reapExpired(now time.Time) {
+	for _, s := range l.shards {
+		s.mu.Lock()
+		for key, e := range s.items {
+			if now.After(e.expires) {
+				delete(s.items, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (l *localCache) shardFor(key string) *localCacheShard {
+	var h uint32
+	for i := 0; i < len(key); i++ {
+		h = h*31 + uint32(key[i])
+	}
+
+	return l.shards[h%localCacheShardNum]
+}
+
+func (l *localCache) get(key string) (any, bool) {
+	s := l.shardFor(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.items[key]
+	if !ok || time.Now().After(e.expires) || e.gen != atomic.LoadUint64(&l.gen) {
+		return nil, false
+	}
+
+	return e.value, true
+}
+
+func (l *localCache) set(key string, value any) {
+	s := l.shardFor(key)
+	s.mu.Lock()
+	s.items[key] = localCacheEntry{value: value, expires: time.Now().Add(localCacheTTL), gen: atomic.LoadUint64(&l.gen)}
+	s.mu.Unlock()
+}
+
+func (l *localCache) del(keys ...string) {
+	for _, key := range keys {
+		s := l.shardFor(key)
+		s.mu.Lock()
+		delete(s.items, key)
+		s.mu.Unlock()
+	}
+}
+
+// subscribe mirrors invalidations published by other instances into this
+// process' L1 cache. The subscription is re-established whenever a health
+// check Ping fails; each reconnect bumps gen first, invalidating every entry
+// cached before the gap since this instance can't know what invalidations it
+// missed while disconnected. Channel() exiting isn't used as the disconnect
+// signal: go-redis reconnects it transparently on transient errors without
+// closing it, so the loop would otherwise sit on a silently stale connection
+// indefinitely.
+func (l *localCache) subscribe(rdb redis.UniversalClient) {
+	if rdb == nil {
+		return
+	}
+	go func() {
+		for {
+			atomic.AddUint64(&l.gen, 1)
+			l.runSubscription(rdb)
+			log.ZWarn(context.Background(), "group L1 cache invalidation subscription dropped, resubscribing", nil)
+			time.Sleep(time.Second)
+		}
+	}()
+}
+
+// runSubscription owns one *redis.PubSub for its lifetime: it consumes
+// messages until a periodic Ping reports the connection dead, then closes it
+// before returning so subscribe's caller can't leak connections across
+// reconnects.
+func (l *localCache) runSubscription(rdb redis.UniversalClient) {
+	sub := rdb.Subscribe(context.Background(), groupLocalInvalidateChannel)
+	defer sub.Close()
+
+	msgs := sub.Channel()
+	ticker := time.NewTicker(localCacheSubscribePingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+			if msg.Payload == "" {
+				continue
+			}
+			l.del(strings.Split(msg.Payload, ",")...)
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+			err := sub.Ping(ctx)
+			cancel()
+			if err != nil {
+				log.ZWarn(context.Background(), "group L1 cache invalidation subscription ping failed", err)
+
+				return
+			}
+		}
+	}
+}
+
+// publishLocalInvalidate notifies other instances to drop the given keys from
+// their L1 cache. It is fired in the background with its own short-lived
+// context, so ExecDel does not wait on it; a failed publish only costs the
+// other instances a stale read until localCacheTTL.
+func publishLocalInvalidate(rdb redis.UniversalClient, keys []string) {
+	if rdb == nil || len(keys) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+		if err := rdb.Publish(ctx, groupLocalInvalidateChannel, strings.Join(keys, ",")).Err(); err != nil {
+			log.ZWarn(ctx, "publish group L1 cache invalidation failed", err, "keys", keys)
+		}
+	}()
+}
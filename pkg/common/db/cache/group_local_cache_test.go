@@ -0,0 +1,42 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLocalCacheReapExpiredPurgesStaleEntries checks that reapExpired deletes
+// an entry past its TTL, rather than leaving it for get() to lazily skip
+// forever, so a long-running process's L1 cache doesn't grow unbounded.
+func TestLocalCacheReapExpiredPurgesStaleEntries(t *testing.T) {
+	l := newLocalCache()
+	l.set("live", "v1")
+
+	s := l.shardFor("expired")
+	s.mu.Lock()
+	s.items["expired"] = localCacheEntry{value: "v2", expires: time.Now().Add(-time.Minute)}
+	s.mu.Unlock()
+
+	l.reapExpired(time.Now())
+
+	if _, ok := l.shardFor("expired").items["expired"]; ok {
+		t.Fatal("reapExpired left an expired entry in the shard map")
+	}
+	if _, ok := l.shardFor("live").items["live"]; !ok {
+		t.Fatal("reapExpired deleted a live entry")
+	}
+}
@@ -2,17 +2,36 @@ package newmgo
 
 import (
 	"context"
+	"regexp"
+
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/newmgo/mgotool"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/pagination"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// groupMemberIndex backs Find/SearchMember/PageMembers' (group_id, user_id,
+// role_level) filters with a compound index, so those queries scan an index
+// instead of the whole collection on large groups.
+var groupMemberIndex = mongo.IndexModel{
+	Keys: bson.D{
+		{Key: "group_id", Value: 1},
+		{Key: "user_id", Value: 1},
+		{Key: "role_level", Value: 1},
+	},
+}
+
 func NewGroupMember(db *mongo.Database) (relation.GroupMemberModelInterface, error) {
-	return &GroupMemberMgo{coll: db.Collection("group_member")}, nil
+	coll := db.Collection("group_member")
+	if _, err := coll.Indexes().CreateOne(context.Background(), groupMemberIndex); err != nil {
+		return nil, err
+	}
+
+	return &GroupMemberMgo{coll: coll}, nil
 }
 
 type GroupMemberMgo struct {
@@ -31,9 +50,31 @@ func (g *GroupMemberMgo) Update(ctx context.Context, groupID string, userID stri
 	return mgotool.UpdateOne(ctx, g.coll, bson.M{"group_id": groupID, "user_id": userID}, bson.M{"$set": data}, true)
 }
 
+// Find returns group members matching groupIDs/userIDs/roleLevels. Any empty
+// slice is treated as "no filter" on that field, so e.g. passing only
+// userIDs returns every group those users belong to.
 func (g *GroupMemberMgo) Find(ctx context.Context, groupIDs []string, userIDs []string, roleLevels []int32) (groupMembers []*relation.GroupMemberModel, err error) {
-	//TODO implement me
-	panic("implement me")
+	filter := groupMemberFilter(groupIDs, userIDs, roleLevels)
+
+	return mgotool.Find[*relation.GroupMemberModel](ctx, g.coll, filter)
+}
+
+// groupMemberFilter builds the compound (group_id, user_id, role_level)
+// filter shared by Find and SearchMember, leaving out any field whose slice
+// is empty so it doesn't constrain the query.
+func groupMemberFilter(groupIDs []string, userIDs []string, roleLevels []int32) bson.M {
+	filter := bson.M{}
+	if len(groupIDs) > 0 {
+		filter["group_id"] = bson.M{"$in": groupIDs}
+	}
+	if len(userIDs) > 0 {
+		filter["user_id"] = bson.M{"$in": userIDs}
+	}
+	if len(roleLevels) > 0 {
+		filter["role_level"] = bson.M{"$in": roleLevels}
+	}
+
+	return filter
 }
 
 func (g *GroupMemberMgo) FindMemberUserID(ctx context.Context, groupID string) (userIDs []string, err error) {
@@ -48,9 +89,96 @@ func (g *GroupMemberMgo) TakeOwner(ctx context.Context, groupID string) (groupMe
 	return mgotool.FindOne[*relation.GroupMemberModel](ctx, g.coll, bson.M{"group_id": groupID, "role_level": constant.GroupOwner})
 }
 
+// groupMemberFacetResult is the shape of the single $facet document Mongo
+// returns: the page of matched members alongside the total match count,
+// fetched in one round trip instead of a Find + a separate CountDocuments.
+type groupMemberFacetResult struct {
+	Data  []*relation.GroupMemberModel `bson:"data"`
+	Count []struct {
+		Total int64 `bson:"total"`
+	} `bson:"count"`
+}
+
+// facetQuery runs match through a single $facet aggregation that returns the
+// [skip, skip+limit) page of matches alongside the total match count in one
+// round trip, instead of a Find plus a separate CountDocuments. SearchMember
+// and PageMembers share this so a fix to the facet shape only has to be made
+// once. The data branch sorts on user_id before skip/limit: Mongo gives no
+// order guarantee across $skip/$limit without an explicit sort, so without
+// one, consecutive page fetches against a group gaining/losing members could
+// return duplicate or skipped members.
+func (g *GroupMemberMgo) facetQuery(ctx context.Context, match bson.M, skip, limit int64) (total int64, groupMembers []*relation.GroupMemberModel, err error) {
+	cursor, err := g.coll.Aggregate(ctx, bson.A{
+		bson.M{"$match": match},
+		bson.M{"$facet": bson.M{
+			"data": bson.A{
+				bson.M{"$sort": bson.M{"user_id": 1}},
+				bson.M{"$skip": skip},
+				bson.M{"$limit": limit},
+			},
+			"count": bson.A{bson.M{"$count": "total"}},
+		}},
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []groupMemberFacetResult
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, nil, err
+	}
+	if len(results) == 0 || len(results[0].Count) == 0 {
+		return 0, nil, nil
+	}
+
+	return results[0].Count[0].Total, results[0].Data, nil
+}
+
 func (g *GroupMemberMgo) SearchMember(ctx context.Context, keyword string, groupIDs []string, userIDs []string, roleLevels []int32, pagination pagination.Pagination) (total int64, groupList []*relation.GroupMemberModel, err error) {
-	//TODO implement me
-	panic("implement me")
+	match := groupMemberFilter(groupIDs, userIDs, roleLevels)
+	if keyword != "" {
+		match["nickname"] = primitive.Regex{Pattern: regexp.QuoteMeta(keyword), Options: "i"}
+	}
+	skip := int64((pagination.GetPageNumber() - 1) * pagination.GetShowNumber())
+	limit := int64(pagination.GetShowNumber())
+
+	return g.facetQuery(ctx, match, skip, limit)
+}
+
+// PageMembers pages group_member directly in Mongo via $skip/$limit instead
+// of loading every member ID into the application first. userIDs preserves
+// the intersecting semantics the old in-memory utils.BothExist pagination
+// provided: nil means "no filter" (every member), while a non-nil slice -
+// including an explicitly empty one - constrains the result to that set, so
+// an empty-but-non-nil filter correctly yields zero members rather than all
+// of them.
+func (g *GroupMemberMgo) PageMembers(ctx context.Context, groupID string, userIDs []string, roleLevels []int32, pageNumber, showNumber int32) (total int64, groupMembers []*relation.GroupMemberModel, err error) {
+	filter := pageMembersFilter(groupID, userIDs, roleLevels)
+	skip := int64((pageNumber - 1) * showNumber)
+	limit := int64(showNumber)
+
+	return g.facetQuery(ctx, filter, skip, limit)
+}
+
+// pageMembersFilter builds PageMembers' filter, preserving the intersecting
+// semantics the old in-memory utils.BothExist pagination provided: nil
+// userIDs means "no filter" (every member), while a non-nil slice -
+// including an explicitly empty one - constrains the result to that set, so
+// an empty-but-non-nil filter correctly yields zero members rather than all
+// of them. roleLevels follows groupMemberFilter's "empty means no filter"
+// rule instead, since PageMembers has no caller that needs to distinguish
+// "no role filter" from "filter to zero roles".
+func pageMembersFilter(groupID string, userIDs []string, roleLevels []int32) bson.M {
+	filter := bson.M{"group_id": groupID}
+	if len(roleLevels) > 0 {
+		filter["role_level"] = bson.M{"$in": roleLevels}
+	}
+	if userIDs != nil {
+		filter["user_id"] = bson.M{"$in": userIDs}
+	}
+
+	return filter
 }
 
 func (g *GroupMemberMgo) FindUserJoinedGroupID(ctx context.Context, userID string) (groupIDs []string, err error) {
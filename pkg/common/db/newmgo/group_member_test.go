@@ -0,0 +1,65 @@
+package newmgo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGroupMemberFilter(t *testing.T) {
+	cases := []struct {
+		name       string
+		groupIDs   []string
+		userIDs    []string
+		roleLevels []int32
+		want       bson.M
+	}{
+		{name: "all empty means no filter", want: bson.M{}},
+		{
+			name:     "groupIDs only",
+			groupIDs: []string{"g1", "g2"},
+			want:     bson.M{"group_id": bson.M{"$in": []string{"g1", "g2"}}},
+		},
+		{
+			name:       "every field set",
+			groupIDs:   []string{"g1"},
+			userIDs:    []string{"u1"},
+			roleLevels: []int32{1},
+			want: bson.M{
+				"group_id":   bson.M{"$in": []string{"g1"}},
+				"user_id":    bson.M{"$in": []string{"u1"}},
+				"role_level": bson.M{"$in": []int32{1}},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := groupMemberFilter(c.groupIDs, c.userIDs, c.roleLevels)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("groupMemberFilter(%v, %v, %v) = %v, want %v", c.groupIDs, c.userIDs, c.roleLevels, got, c.want)
+			}
+		})
+	}
+}
+
+// TestPageMembersFilterNilVsEmpty locks in the nil-vs-empty userIDs contract
+// PageMembers documents: nil must build a filter with no user_id constraint,
+// while a non-nil (even empty) slice must constrain on user_id so it can
+// correctly yield zero members.
+func TestPageMembersFilterNilVsEmpty(t *testing.T) {
+	nilFilter := pageMembersFilter("g1", nil, nil)
+	if _, ok := nilFilter["user_id"]; ok {
+		t.Fatalf("nil userIDs must not constrain user_id, got filter %v", nilFilter)
+	}
+
+	emptyFilter := pageMembersFilter("g1", []string{}, nil)
+	constraint, ok := emptyFilter["user_id"]
+	if !ok {
+		t.Fatalf("non-nil empty userIDs must constrain user_id, got filter %v", emptyFilter)
+	}
+	want := bson.M{"$in": []string{}}
+	if !reflect.DeepEqual(constraint, want) {
+		t.Fatalf("user_id constraint = %v, want %v", constraint, want)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/pagination"
+)
+
+type GroupModel struct {
+	GroupID string `bson:"group_id"`
+}
+
+type GroupMemberModel struct {
+	GroupID   string `bson:"group_id"`
+	UserID    string `bson:"user_id"`
+	RoleLevel int32  `bson:"role_level"`
+}
+
+type GroupSimpleUserID struct {
+	Hash      uint64
+	MemberNum uint32
+}
+
+type GroupModelInterface interface {
+	Take(ctx context.Context, groupID string) (group *GroupModel, err error)
+}
+
+type GroupMemberModelInterface interface {
+	Create(ctx context.Context, groupMembers []*GroupMemberModel) (err error)
+	Delete(ctx context.Context, groupID string, userIDs []string) (err error)
+	Update(ctx context.Context, groupID string, userID string, data map[string]any) (err error)
+	Find(ctx context.Context, groupIDs []string, userIDs []string, roleLevels []int32) (groupMembers []*GroupMemberModel, err error)
+	FindMemberUserID(ctx context.Context, groupID string) (userIDs []string, err error)
+	Take(ctx context.Context, groupID string, userID string) (groupMember *GroupMemberModel, err error)
+	TakeOwner(ctx context.Context, groupID string) (groupMember *GroupMemberModel, err error)
+	SearchMember(ctx context.Context, keyword string, groupIDs []string, userIDs []string, roleLevels []int32, pagination pagination.Pagination) (total int64, groupList []*GroupMemberModel, err error)
+	FindUserJoinedGroupID(ctx context.Context, userID string) (groupIDs []string, err error)
+	TakeGroupMemberNum(ctx context.Context, groupID string) (count int64, err error)
+	FindUserManagedGroupID(ctx context.Context, userID string) (groupIDs []string, err error)
+	// PageMembers pages group_member in the database (e.g. $skip/$limit in
+	// Mongo) instead of requiring callers to load every member ID and
+	// paginate in memory. roleLevels follows Find's "empty means no filter"
+	// rule, but userIDs preserves the stricter nil-vs-empty contract the old
+	// in-memory pagination relied on: nil means no filter, while a non-nil
+	// (even empty) slice constrains the result to that set.
+	PageMembers(ctx context.Context, groupID string, userIDs []string, roleLevels []int32, pageNumber, showNumber int32) (total int64, groupMembers []*GroupMemberModel, err error)
+}
+
+type GroupRequestModelInterface interface {
+}